@@ -0,0 +1,42 @@
+package nserve
+
+import "context"
+
+// App is a running instance of a service built from a set of providers.
+// It ties together the standard Start/Stop/Shutdown hooks (and any
+// others a caller defines) so that lifecycle helpers like HandleSignals
+// have something concrete to act on instead of reaching for the
+// package-level hooks directly.
+type App struct {
+	// Providers are carried alongside the App for whatever built on
+	// top of nserve needs them (e.g. a dependency-injection layer).
+	// Any provider that is an nserve.Callback, or a plain
+	// func(context.Context) error, additionally runs, in this slice's
+	// order, as a callback of the hook passed to Invoke (not of that
+	// hook's InvokeOnError hooks); providers of other types are not
+	// otherwise interpreted by Invoke.
+	Providers []interface{}
+}
+
+// NewApp creates an App wrapping the given providers.
+func NewApp(providers ...interface{}) *App {
+	return &App{Providers: providers}
+}
+
+// Invoke runs hook against this App: hook's own callbacks run, then
+// any callback-shaped entries from a.Providers (see App.Providers),
+// then anything ctx's HookRegistry has registered for hook (see
+// WithHooks/RegisterOn) — all without copying hook, so
+// context-registered callbacks (which are matched against hook by
+// identity) still fire even when the App also has provider callbacks.
+// This lets sub-components constructed inside a request or boot
+// context add Stop/Shutdown callbacks without ever seeing this App.
+func (a *App) Invoke(ctx context.Context, hook *Hook) error {
+	var cbs []Callback
+	for _, p := range a.Providers {
+		if cb, ok := asCallback(p); ok {
+			cbs = append(cbs, cb)
+		}
+	}
+	return hook.invoke(ctx, cbs)
+}
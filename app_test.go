@@ -0,0 +1,39 @@
+package nserve
+
+import (
+	"context"
+	"testing"
+)
+
+// TestAppInvokeCombinesProvidersAndContextRegistry is a regression test
+// for a bug where App.Invoke invoked a Hook.Copy() of the hook whenever
+// it had provider callbacks to add, which broke the pointer-identity
+// match RegisterOn/callbacksForHook rely on: any context-registered
+// callback was silently dropped as soon as the App also had a provider
+// callback. Both must fire together.
+func TestAppInvokeCombinesProvidersAndContextRegistry(t *testing.T) {
+	h := NewHook("both", ForwardOrder)
+
+	providerRan := false
+	app := NewApp(Callback(func(context.Context) error {
+		providerRan = true
+		return nil
+	}))
+
+	contextRan := false
+	ctx := WithHooks(context.Background())
+	RegisterOn(ctx, h, Callback(func(context.Context) error {
+		contextRan = true
+		return nil
+	}))
+
+	if err := app.Invoke(ctx, h); err != nil {
+		t.Fatalf("Invoke() = %v, want nil", err)
+	}
+	if !providerRan {
+		t.Error("provider callback did not run")
+	}
+	if !contextRan {
+		t.Error("context-registered callback did not run alongside the provider callback")
+	}
+}
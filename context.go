@@ -0,0 +1,118 @@
+package nserve
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// hookRegSeq is a global counter used to order HookRegistry entries
+// across nested scopes, the same way hookCounter orders Hooks.
+var hookRegSeq int64
+
+type hooksContextKey struct{}
+
+// HookRegistry lets code that only has a context.Context, not an *App,
+// register hook callbacks. It is seeded into a context with WithHooks
+// and read back with HooksFromContext; RegisterOn is the usual way to
+// add to it.
+type HookRegistry struct {
+	parent *HookRegistry
+
+	mu      sync.Mutex
+	entries []hookRegistration
+}
+
+type hookRegistration struct {
+	seq      int64
+	hook     *Hook
+	provider interface{}
+}
+
+// WithHooks returns a context carrying a fresh HookRegistry. If ctx
+// already carries one (from an enclosing WithHooks call), the new
+// registry is chained beneath it as a child scope: providers
+// registered at either level are still visited in the order they were
+// registered, not grouped by scope.
+func WithHooks(ctx context.Context) context.Context {
+	parent, _ := ctx.Value(hooksContextKey{}).(*HookRegistry)
+	return context.WithValue(ctx, hooksContextKey{}, &HookRegistry{parent: parent})
+}
+
+// HooksFromContext returns the HookRegistry seeded by the nearest
+// enclosing WithHooks call, or nil if ctx does not carry one.
+func HooksFromContext(ctx context.Context) *HookRegistry {
+	r, _ := ctx.Value(hooksContextKey{}).(*HookRegistry)
+	return r
+}
+
+// RegisterOn attaches provider to h within the HookRegistry carried by
+// ctx, so that code constructing subsystems inside a request or boot
+// context can add Start/Stop/Shutdown callbacks without being passed
+// the *App directly. provider must be an nserve.Callback, or a plain
+// func(context.Context) error, which is the same thing spelled out.
+// RegisterOn panics if ctx does not carry a registry; call WithHooks
+// first.
+func RegisterOn(ctx context.Context, h *Hook, provider interface{}) {
+	r := HooksFromContext(ctx)
+	if r == nil {
+		panic("nserve.RegisterOn: ctx does not carry a hook registry; call nserve.WithHooks first")
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries = append(r.entries, hookRegistration{
+		seq:      atomic.AddInt64(&hookRegSeq, 1),
+		hook:     h,
+		provider: provider,
+	})
+}
+
+// callbacksForHook collects every provider registered for hook across
+// r and its enclosing scopes, sorted by registration order, regardless
+// of which scope each one came from.
+func (r *HookRegistry) callbacksForHook(hook *Hook) []Callback {
+	if r == nil {
+		return nil
+	}
+	var matched []hookRegistration
+	for reg := r; reg != nil; reg = reg.parent {
+		reg.mu.Lock()
+		for _, e := range reg.entries {
+			if e.hook == hook {
+				matched = append(matched, e)
+			}
+		}
+		reg.mu.Unlock()
+	}
+	sort.Slice(matched, func(i, j int) bool { return matched[i].seq < matched[j].seq })
+	cbs := make([]Callback, len(matched))
+	for i, e := range matched {
+		cbs[i] = providerCallback(e.provider)
+	}
+	return cbs
+}
+
+func providerCallback(provider interface{}) Callback {
+	if cb, ok := asCallback(provider); ok {
+		return cb
+	}
+	return func(context.Context) error {
+		return fmt.Errorf("nserve: provider of type %T registered with RegisterOn is not a Callback", provider)
+	}
+}
+
+// asCallback reports whether provider is an nserve.Callback, or a
+// plain func(context.Context) error (the same signature spelled out),
+// and if so returns it as a Callback.
+func asCallback(provider interface{}) (Callback, bool) {
+	switch p := provider.(type) {
+	case Callback:
+		return p, true
+	case func(context.Context) error:
+		return Callback(p), true
+	default:
+		return nil, false
+	}
+}
@@ -0,0 +1,121 @@
+package nserve
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestRegisterOnRunsInRegistrationOrder(t *testing.T) {
+	h := NewHook("ctx", ForwardOrder)
+	ctx := WithHooks(context.Background())
+
+	var order []string
+	RegisterOn(ctx, h, Callback(func(context.Context) error {
+		order = append(order, "first")
+		return nil
+	}))
+	RegisterOn(ctx, h, Callback(func(context.Context) error {
+		order = append(order, "second")
+		return nil
+	}))
+
+	if err := h.InvokeContext(ctx); err != nil {
+		t.Fatalf("InvokeContext() = %v, want nil", err)
+	}
+	if len(order) != 2 || order[0] != "first" || order[1] != "second" {
+		t.Fatalf("callback order = %v, want [first second]", order)
+	}
+}
+
+func TestRegisterOnOnlyMatchesItsOwnHook(t *testing.T) {
+	h1 := NewHook("one", ForwardOrder)
+	h2 := NewHook("two", ForwardOrder)
+	ctx := WithHooks(context.Background())
+
+	h2Ran := false
+	RegisterOn(ctx, h2, Callback(func(context.Context) error {
+		h2Ran = true
+		return nil
+	}))
+
+	if err := h1.InvokeContext(ctx); err != nil {
+		t.Fatalf("InvokeContext() = %v, want nil", err)
+	}
+	if h2Ran {
+		t.Fatal("h1.InvokeContext ran a callback registered against h2")
+	}
+}
+
+func TestRegisterOnNestedScopesBothVisited(t *testing.T) {
+	h := NewHook("nested", ForwardOrder)
+	outer := WithHooks(context.Background())
+
+	outerRan, innerRan := false, false
+	RegisterOn(outer, h, Callback(func(context.Context) error {
+		outerRan = true
+		return nil
+	}))
+
+	inner := WithHooks(outer)
+	RegisterOn(inner, h, Callback(func(context.Context) error {
+		innerRan = true
+		return nil
+	}))
+
+	if err := h.InvokeContext(inner); err != nil {
+		t.Fatalf("InvokeContext() = %v, want nil", err)
+	}
+	if !outerRan || !innerRan {
+		t.Fatalf("outerRan=%v innerRan=%v, want both true", outerRan, innerRan)
+	}
+}
+
+func TestRegisterOnPanicsWithoutWithHooks(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("RegisterOn did not panic when ctx carries no HookRegistry")
+		}
+	}()
+	RegisterOn(context.Background(), NewHook("unseeded", ForwardOrder), Callback(func(context.Context) error { return nil }))
+}
+
+func TestRegisterOnRejectsNonCallbackProvider(t *testing.T) {
+	h := NewHook("badprovider", ForwardOrder)
+	ctx := WithHooks(context.Background())
+	RegisterOn(ctx, h, "not a callback")
+
+	err := h.InvokeContext(ctx)
+	if err == nil {
+		t.Fatal("InvokeContext() = nil, want error for a non-Callback provider")
+	}
+}
+
+func TestAsCallbackAcceptsBothSpellings(t *testing.T) {
+	if _, ok := asCallback(Callback(func(context.Context) error { return nil })); !ok {
+		t.Error("asCallback rejected a Callback value")
+	}
+	if _, ok := asCallback(func(context.Context) error { return nil }); !ok {
+		t.Error("asCallback rejected a plain func(context.Context) error value")
+	}
+	if _, ok := asCallback(42); ok {
+		t.Error("asCallback accepted an int")
+	}
+}
+
+func TestHooksFromContextNilWhenUnseeded(t *testing.T) {
+	if r := HooksFromContext(context.Background()); r != nil {
+		t.Fatalf("HooksFromContext(context.Background()) = %v, want nil", r)
+	}
+}
+
+func TestProviderCallbackWrapsNonCallbackError(t *testing.T) {
+	cb := providerCallback(123)
+	err := cb(context.Background())
+	if err == nil {
+		t.Fatal("providerCallback(123)(ctx) = nil, want error")
+	}
+	if errors.Is(err, context.Canceled) {
+		t.Fatal("unexpected error identity")
+	}
+}
@@ -0,0 +1,101 @@
+package nserve
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrorStack accumulates every error produced while invoking a hook's
+// callbacks, preserving each one instead of folding them pairwise into
+// a single combined error. It satisfies the error interface, and
+// errors.Is/errors.As traverse every element it holds, not just the
+// most recent one, so an OnError hook can pattern-match against any
+// single failure among many.
+type ErrorStack struct {
+	errs []error
+}
+
+// Push appends err to the top of the stack. Push is a no-op if err is
+// nil.
+func (s *ErrorStack) Push(err error) {
+	if err == nil {
+		return
+	}
+	s.errs = append(s.errs, err)
+}
+
+// Pop removes and returns the most recently pushed error, or nil if the
+// stack is empty.
+func (s *ErrorStack) Pop() error {
+	if len(s.errs) == 0 {
+		return nil
+	}
+	last := s.errs[len(s.errs)-1]
+	s.errs = s.errs[:len(s.errs)-1]
+	return last
+}
+
+// Seek returns the error at position i, where 0 is the first error
+// pushed, or nil if i is out of range.
+func (s *ErrorStack) Seek(i int) error {
+	if i < 0 || i >= len(s.errs) {
+		return nil
+	}
+	return s.errs[i]
+}
+
+// Errors returns the accumulated errors in the order they were pushed.
+// The returned slice is a copy; mutating it does not affect the stack.
+func (s *ErrorStack) Errors() []error {
+	out := make([]error, len(s.errs))
+	copy(out, s.errs)
+	return out
+}
+
+// Len returns the number of errors on the stack.
+func (s *ErrorStack) Len() int {
+	return len(s.errs)
+}
+
+// Error implements the error interface, joining every accumulated error
+// one per line. An empty stack returns the empty string.
+func (s *ErrorStack) Error() string {
+	switch len(s.errs) {
+	case 0:
+		return ""
+	case 1:
+		return s.errs[0].Error()
+	default:
+		var b strings.Builder
+		for i, e := range s.errs {
+			if i > 0 {
+				b.WriteByte('\n')
+			}
+			b.WriteString(e.Error())
+		}
+		return b.String()
+	}
+}
+
+// Is reports whether any error on the stack matches target, per
+// errors.Is semantics. This lets errors.Is(err, target) succeed against
+// an ErrorStack without the caller needing to unwrap it by hand.
+func (s *ErrorStack) Is(target error) bool {
+	for _, e := range s.errs {
+		if errors.Is(e, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds the first error on the stack that matches target, per
+// errors.As semantics, and if found, sets target and returns true.
+func (s *ErrorStack) As(target interface{}) bool {
+	for _, e := range s.errs {
+		if errors.As(e, target) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,113 @@
+package nserve
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestErrorStackPushPopSeek(t *testing.T) {
+	var s ErrorStack
+	if s.Len() != 0 {
+		t.Fatalf("Len() = %d, want 0", s.Len())
+	}
+	if s.Pop() != nil {
+		t.Fatal("Pop() on empty stack should return nil")
+	}
+
+	first := errors.New("first")
+	second := errors.New("second")
+	s.Push(nil) // no-op
+	s.Push(first)
+	s.Push(second)
+
+	if s.Len() != 2 {
+		t.Fatalf("Len() = %d, want 2", s.Len())
+	}
+	if got := s.Seek(0); got != first {
+		t.Fatalf("Seek(0) = %v, want %v", got, first)
+	}
+	if got := s.Seek(1); got != second {
+		t.Fatalf("Seek(1) = %v, want %v", got, second)
+	}
+	if got := s.Seek(2); got != nil {
+		t.Fatalf("Seek(2) = %v, want nil (out of range)", got)
+	}
+	if got := s.Seek(-1); got != nil {
+		t.Fatalf("Seek(-1) = %v, want nil (out of range)", got)
+	}
+
+	if got := s.Pop(); got != second {
+		t.Fatalf("Pop() = %v, want %v", got, second)
+	}
+	if s.Len() != 1 {
+		t.Fatalf("Len() after Pop() = %d, want 1", s.Len())
+	}
+}
+
+func TestErrorStackErrorsIsCopy(t *testing.T) {
+	var s ErrorStack
+	e1, e2 := errors.New("e1"), errors.New("e2")
+	s.Push(e1)
+	s.Push(e2)
+
+	got := s.Errors()
+	got[0] = errors.New("mutated")
+	if s.Seek(0) != e1 {
+		t.Fatal("Errors() did not return a copy; mutating it changed the stack")
+	}
+}
+
+func TestErrorStackErrorString(t *testing.T) {
+	var empty ErrorStack
+	if empty.Error() != "" {
+		t.Fatalf("Error() on empty stack = %q, want empty string", empty.Error())
+	}
+
+	var one ErrorStack
+	one.Push(errors.New("solo"))
+	if one.Error() != "solo" {
+		t.Fatalf("Error() with one error = %q, want %q", one.Error(), "solo")
+	}
+
+	var many ErrorStack
+	many.Push(errors.New("a"))
+	many.Push(errors.New("b"))
+	want := "a\nb"
+	if many.Error() != want {
+		t.Fatalf("Error() with two errors = %q, want %q", many.Error(), want)
+	}
+}
+
+func TestErrorStackIsAndAs(t *testing.T) {
+	sentinel := errors.New("sentinel")
+	wrapped := fmtErrorf(sentinel)
+	other := errors.New("other")
+
+	var s ErrorStack
+	s.Push(wrapped)
+	s.Push(other)
+
+	if !errors.Is(&s, sentinel) {
+		t.Fatal("errors.Is(&s, sentinel) = false, want true; sentinel is buried under a later error")
+	}
+	if errors.Is(&s, errors.New("not present")) {
+		t.Fatal("errors.Is(&s, unrelated) = true, want false")
+	}
+
+	var target *wrappedError
+	if !errors.As(&s, &target) {
+		t.Fatal("errors.As(&s, &target) = false, want true")
+	}
+}
+
+// wrappedError and fmtErrorf exist only so the As test has a concrete
+// type to unwrap into, without pulling in fmt.Errorf's %w semantics
+// via a separate top-level helper.
+type wrappedError struct{ inner error }
+
+func (w *wrappedError) Error() string { return "wrapped: " + w.inner.Error() }
+func (w *wrappedError) Unwrap() error { return w.inner }
+
+func fmtErrorf(inner error) error {
+	return &wrappedError{inner: inner}
+}
@@ -0,0 +1,180 @@
+// Package graceful implements zero-downtime restarts for services
+// built on nserve. Listening sockets registered on nserve.Restart with
+// nserve.Hook.RegisterListener (typically from a Start callback) are
+// duplicated into inheritable file descriptors and handed to a freshly
+// exec'd copy of the running binary using the LISTEN_FDS environment
+// variable convention popularized by systemd socket activation. The
+// parent keeps serving until the child reports readiness over a pipe,
+// then invokes nserve.Stop so it can drain in-flight work and exit.
+package graceful
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+
+	"github.com/muir/nserve"
+)
+
+// listenFDStart is the first inherited file descriptor a child looks
+// at, per the systemd socket-activation convention.
+const listenFDStart = 3
+
+// readyFDEnv names the environment variable Restart uses to tell the
+// child which inherited file descriptor to write to, via Ready, once
+// it has finished starting up. It is not part of the systemd
+// convention; LISTEN_FDS alone has no way to signal readiness back to
+// the parent.
+const readyFDEnv = "NSERVE_READY_FD"
+
+// Restart gathers the listeners registered on nserve.Restart,
+// duplicates them into inheritable file descriptors, execs a copy of
+// the running binary with those descriptors and LISTEN_FDS set, waits
+// for the child to call Ready, and then invokes nserve.Stop on the
+// parent so it can drain and exit. The child process is expected to
+// call InheritedListeners to pick the sockets back up and Ready once
+// it's serving on them.
+func Restart(ctx context.Context) error {
+	listeners := nserve.Restart.Listeners()
+
+	files := make([]*os.File, 0, len(listeners))
+	for _, l := range listeners {
+		f, err := listenerFile(l)
+		if err != nil {
+			return fmt.Errorf("graceful restart: %w", err)
+		}
+		files = append(files, f)
+	}
+
+	readPipe, writePipe, err := os.Pipe()
+	if err != nil {
+		return fmt.Errorf("graceful restart: create ready pipe: %w", err)
+	}
+	defer readPipe.Close()
+
+	// LISTEN_PID is deliberately not set here: systemd's convention
+	// has consumers check it against their own pid to guard against
+	// picking up fds meant for a different process, but os.StartProcess
+	// forks and execs in one call, so the child's pid isn't known until
+	// after exec has already replaced its environment. InheritedListeners
+	// documents that it does not check LISTEN_PID for the same reason.
+	readyFD := listenFDStart + len(files)
+	env := append(os.Environ(),
+		fmt.Sprintf("LISTEN_FDS=%d", len(files)),
+		fmt.Sprintf("%s=%d", readyFDEnv, readyFD),
+	)
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("graceful restart: %w", err)
+	}
+	argv0, err := exec.LookPath(os.Args[0])
+	if err != nil {
+		argv0 = os.Args[0]
+	}
+
+	procFiles := append([]*os.File{os.Stdin, os.Stdout, os.Stderr}, files...)
+	procFiles = append(procFiles, writePipe)
+
+	proc, err := os.StartProcess(argv0, os.Args, &os.ProcAttr{
+		Dir:   wd,
+		Env:   env,
+		Files: procFiles,
+	})
+	// The duplicated listener fds (and the write end of the ready
+	// pipe) were only needed to seed the child's fd table; StartProcess
+	// has already copied them across exec, so the parent's copies must
+	// be closed here or every restart leaks one fd per listener.
+	for _, f := range files {
+		f.Close()
+	}
+	writePipe.Close()
+	if err != nil {
+		return fmt.Errorf("graceful restart: start child: %w", err)
+	}
+
+	ready := make(chan error, 1)
+	go func() {
+		_, err := readPipe.Read(make([]byte, 1))
+		ready <- err
+	}()
+
+	select {
+	case <-ctx.Done():
+		// The child is already running and holds the inherited
+		// listener fds; leaving it alive here would orphan it and
+		// risk a later restart racing with it over those sockets, so
+		// kill it before reporting the timeout.
+		_ = proc.Kill()
+		return fmt.Errorf("graceful restart: waiting for child pid %d to signal readiness: %w", proc.Pid, ctx.Err())
+	case err := <-ready:
+		if err != nil {
+			return fmt.Errorf("graceful restart: child pid %d did not signal readiness: %w", proc.Pid, err)
+		}
+	}
+
+	return nserve.Stop.InvokeContext(ctx)
+}
+
+// Ready signals a parent process that started us via Restart that
+// startup is complete and it's safe for the parent to run nserve.Stop
+// and exit. Ready is a no-op if this process was not started via
+// Restart.
+func Ready() error {
+	fdStr := os.Getenv(readyFDEnv)
+	if fdStr == "" {
+		return nil
+	}
+	fd, err := strconv.Atoi(fdStr)
+	if err != nil {
+		return fmt.Errorf("graceful: bad %s %q: %w", readyFDEnv, fdStr, err)
+	}
+	f := os.NewFile(uintptr(fd), "ready")
+	defer f.Close()
+	_, err = f.Write([]byte{1})
+	return err
+}
+
+// InheritedListeners reconstructs the listening sockets passed through
+// by a parent process performing a graceful restart, using the
+// LISTEN_FDS file descriptors the systemd socket-activation convention
+// describes. If LISTEN_FDS is unset or zero, InheritedListeners returns
+// an empty slice: that's the normal case for a process that wasn't
+// started via Restart. Unlike a strict systemd client, it does not
+// check LISTEN_PID, since the child's pid can't be known until after
+// the parent has already called exec.
+func InheritedListeners() ([]net.Listener, error) {
+	n, err := strconv.Atoi(os.Getenv("LISTEN_FDS"))
+	if err != nil || n <= 0 {
+		return nil, nil
+	}
+	listeners := make([]net.Listener, 0, n)
+	for i := 0; i < n; i++ {
+		fd := listenFDStart + i
+		f := os.NewFile(uintptr(fd), fmt.Sprintf("listener-%d", i))
+		l, err := net.FileListener(f)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("graceful: inherit listener fd %d: %w", fd, err)
+		}
+		listeners = append(listeners, l)
+	}
+	return listeners, nil
+}
+
+// listenerFile extracts the underlying file descriptor of a listener
+// so it can be passed through exec to a child process. Only the
+// listener types that expose a File method support this.
+func listenerFile(l net.Listener) (*os.File, error) {
+	switch t := l.(type) {
+	case *net.TCPListener:
+		return t.File()
+	case *net.UnixListener:
+		return t.File()
+	default:
+		return nil, fmt.Errorf("listener of type %T cannot be inherited across a graceful restart", l)
+	}
+}
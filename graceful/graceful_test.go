@@ -0,0 +1,84 @@
+package graceful
+
+import (
+	"os"
+	"strconv"
+	"testing"
+)
+
+func TestReadyNoopWithoutEnv(t *testing.T) {
+	os.Unsetenv(readyFDEnv)
+	if err := Ready(); err != nil {
+		t.Fatalf("Ready() = %v, want nil when %s is unset", err, readyFDEnv)
+	}
+}
+
+func TestReadyWritesToFD(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("os.Pipe: %v", err)
+	}
+	defer r.Close()
+
+	t.Setenv(readyFDEnv, strconv.Itoa(int(w.Fd())))
+
+	if err := Ready(); err != nil {
+		t.Fatalf("Ready() = %v, want nil", err)
+	}
+
+	buf := make([]byte, 1)
+	if _, err := r.Read(buf); err != nil {
+		t.Fatalf("reading readiness byte: %v", err)
+	}
+}
+
+func TestReadyBadFDEnv(t *testing.T) {
+	t.Setenv(readyFDEnv, "not-a-number")
+	if err := Ready(); err == nil {
+		t.Fatal("Ready() = nil, want error for a non-numeric NSERVE_READY_FD")
+	}
+}
+
+func TestInheritedListenersNoEnv(t *testing.T) {
+	os.Unsetenv("LISTEN_FDS")
+	ls, err := InheritedListeners()
+	if err != nil {
+		t.Fatalf("InheritedListeners() err = %v, want nil", err)
+	}
+	if len(ls) != 0 {
+		t.Fatalf("InheritedListeners() = %d listeners, want 0 when LISTEN_FDS is unset", len(ls))
+	}
+}
+
+func TestInheritedListenersZero(t *testing.T) {
+	t.Setenv("LISTEN_FDS", "0")
+	ls, err := InheritedListeners()
+	if err != nil {
+		t.Fatalf("InheritedListeners() err = %v, want nil", err)
+	}
+	if len(ls) != 0 {
+		t.Fatalf("InheritedListeners() = %d listeners, want 0 when LISTEN_FDS=0", len(ls))
+	}
+}
+
+func TestInheritedListenersNegative(t *testing.T) {
+	t.Setenv("LISTEN_FDS", "-1")
+	ls, err := InheritedListeners()
+	if err != nil {
+		t.Fatalf("InheritedListeners() err = %v, want nil", err)
+	}
+	if len(ls) != 0 {
+		t.Fatalf("InheritedListeners() = %d listeners, want 0 for a negative LISTEN_FDS", len(ls))
+	}
+}
+
+func TestInheritedListenersBadEnv(t *testing.T) {
+	t.Setenv("LISTEN_FDS", "not-a-number")
+	ls, err := InheritedListeners()
+	if err != nil {
+		t.Fatalf("InheritedListeners() err = %v, want nil (atoi failure is treated the same as unset)", err)
+	}
+	if len(ls) != 0 {
+		t.Fatalf("InheritedListeners() = %d listeners, want 0", len(ls))
+	}
+}
@@ -0,0 +1,62 @@
+package nserve
+
+import (
+	"context"
+	"math/rand"
+	"time"
+)
+
+// Jitter sets the fraction of RunPeriodic's interval that each wait is
+// randomized by, so that periodic hooks across a fleet don't wake up in
+// lockstep. frac should be between 0 (no jitter, the default) and 1. A
+// given wait of d becomes d * (1 + frac*(rand.Float64()*2-1)).
+// Jitter is thread-safe.
+func (h *Hook) Jitter(frac float64) *Hook {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.jitter = frac
+	return h
+}
+
+// RunPeriodic invokes the hook on interval until ctx is canceled,
+// feeding any resulting error through the hook's normal InvokeOnError
+// chain. It schedules the next wait only once the current invocation
+// has returned, so a callback that overruns interval simply delays the
+// next run instead of a second invocation starting concurrently with
+// it: periodic invocations are never queued up. After an error, the
+// wait before the next invocation doubles, capped at 8x interval, to
+// back off from a dependency that's already failing; a successful
+// invocation resets the wait back to interval.
+func (h *Hook) RunPeriodic(ctx context.Context, interval time.Duration) {
+	h.lock.Lock()
+	jitter := h.jitter
+	h.lock.Unlock()
+
+	wait := interval
+	timer := time.NewTimer(jitteredWait(wait, jitter))
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+		if err := h.InvokeContext(ctx); err != nil {
+			wait *= 2
+			if max := interval * 8; wait > max {
+				wait = max
+			}
+		} else {
+			wait = interval
+		}
+		timer.Reset(jitteredWait(wait, jitter))
+	}
+}
+
+func jitteredWait(d time.Duration, frac float64) time.Duration {
+	if frac <= 0 {
+		return d
+	}
+	offset := frac * (rand.Float64()*2 - 1)
+	return time.Duration(float64(d) * (1 + offset))
+}
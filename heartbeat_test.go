@@ -0,0 +1,96 @@
+package nserve
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestRunPeriodicSkipsInsteadOfQueueing proves that a callback slower
+// than the interval delays the next invocation rather than a second
+// one starting concurrently with it, and that ticks aren't queued up
+// while a run is in flight.
+func TestRunPeriodicSkipsInsteadOfQueueing(t *testing.T) {
+	h := NewHook("hb", ForwardOrder)
+
+	var inFlight int32
+	var overlapped int32
+	var invocations int32
+
+	h.Register("slow", func(context.Context) error {
+		if !atomic.CompareAndSwapInt32(&inFlight, 0, 1) {
+			atomic.StoreInt32(&overlapped, 1)
+		}
+		atomic.AddInt32(&invocations, 1)
+		time.Sleep(30 * time.Millisecond)
+		atomic.StoreInt32(&inFlight, 0)
+		return nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 150*time.Millisecond)
+	defer cancel()
+	h.RunPeriodic(ctx, 10*time.Millisecond)
+
+	if atomic.LoadInt32(&overlapped) != 0 {
+		t.Fatal("periodic invocations overlapped; a slow run should delay the next tick, not run concurrently with it")
+	}
+	got := atomic.LoadInt32(&invocations)
+	if got == 0 {
+		t.Fatal("callback was never invoked")
+	}
+	// interval is 10ms but each invocation takes 30ms, and the next
+	// 10ms wait starts only once a run returns, so a 150ms window fits
+	// ~4 invocations (one every 40ms). If ticks were queued instead, up
+	// to 15 would pile up.
+	if got > 8 {
+		t.Fatalf("invocations = %d, want roughly 4; ticks that arrive mid-run should be skipped, not queued", got)
+	}
+}
+
+// TestRunPeriodicBackoffOnError proves that the wait before the next
+// invocation grows after an error and resets once a callback succeeds
+// again.
+func TestRunPeriodicBackoffOnError(t *testing.T) {
+	h := NewHook("hb", ForwardOrder)
+
+	var mu sync.Mutex
+	var times []time.Time
+	calls := 0
+
+	h.Register("flaky", func(context.Context) error {
+		mu.Lock()
+		times = append(times, time.Now())
+		calls++
+		n := calls
+		mu.Unlock()
+		if n <= 2 {
+			return errors.New("boom")
+		}
+		return nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 400*time.Millisecond)
+	defer cancel()
+	h.RunPeriodic(ctx, 20*time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(times) < 4 {
+		t.Fatalf("only %d invocations recorded, need at least 4 to observe backoff and reset", len(times))
+	}
+	gapAfterFirstError := times[1].Sub(times[0])
+	gapAfterSecondError := times[2].Sub(times[1])
+	gapAfterSuccess := times[3].Sub(times[2])
+
+	if gapAfterSecondError <= gapAfterFirstError {
+		t.Fatalf("wait after a second consecutive error (%v) did not grow past the wait after the first (%v)",
+			gapAfterSecondError, gapAfterFirstError)
+	}
+	if gapAfterSuccess >= gapAfterSecondError {
+		t.Fatalf("wait after a successful invocation (%v) did not reset back down from the backed-off wait (%v)",
+			gapAfterSuccess, gapAfterSecondError)
+	}
+}
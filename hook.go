@@ -1,8 +1,10 @@
 package nserve
 
 import (
+	"net"
 	"sync"
 	"sync/atomic"
+	"time"
 )
 
 var hookCounter int32
@@ -17,7 +19,11 @@ const (
 	// ReverseOrder is used to indicate that the items
 	// registered for a hook will be invoked opposite to the order
 	// that they were registered.
-	ReverseOrder = "forward"
+	ReverseOrder hookOrder = "reverse"
+	// ParallelOrder is used to indicate that the items registered
+	// for a hook will all be invoked concurrently. Use
+	// Hook.Concurrency to bound how many run at once.
+	ParallelOrder hookOrder = "parallel"
 )
 
 type hookId int32
@@ -32,6 +38,11 @@ type Hook struct {
 	ContinuePast  bool
 	ErrorCombiner func(first, second error) error
 	Providers     []interface{}
+	callbacks     []callbackEntry
+	timeout       time.Duration
+	concurrency   int
+	listeners     []net.Listener
+	jitter        float64
 }
 
 // Copy makes a deep copy of a hook and the new hook gets a new Id.
@@ -43,10 +54,16 @@ func (h *Hook) Copy() *Hook {
 	copy(oe, h.InvokeOnError)
 	op := make([]interface{}, len(h.Providers))
 	copy(op, h.Providers)
+	cb := make([]callbackEntry, len(h.callbacks))
+	copy(cb, h.callbacks)
+	ls := make([]net.Listener, len(h.listeners))
+	copy(ls, h.listeners)
 	hc := *h
 	hc.InvokeOnError = oe
 	hc.Id = hookId(atomic.AddInt32(&hookCounter, 1))
 	hc.Providers = op
+	hc.callbacks = cb
+	hc.listeners = ls
 	hc.lock = new(sync.Mutex)
 	return &hc
 }
@@ -95,6 +112,30 @@ func (h *Hook) ContinuePastError(b bool) *Hook {
 	return h
 }
 
+// WithTimeout sets a deadline that InvokeContext will apply to each
+// callback registered on this hook: the context passed to a callback is
+// canceled d after that callback starts, even if the context passed to
+// InvokeContext has no deadline of its own. A zero duration (the
+// default) means no hook-level timeout is imposed.
+// WithTimeout is thread-safe.
+func (h *Hook) WithTimeout(d time.Duration) *Hook {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.timeout = d
+	return h
+}
+
+// Concurrency sets the maximum number of callbacks that a hook with
+// ParallelOrder will run at once. n <= 0 means unbounded. Concurrency
+// has no effect on hooks with ForwardOrder or ReverseOrder.
+// Concurrency is thread-safe.
+func (h *Hook) Concurrency(n int) *Hook {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.concurrency = n
+	return h
+}
+
 // String is not thread-safe with respect to reaching into a hook and
 // changing it's Name.  Don't do that.
 func (h *Hook) String() string {
@@ -112,3 +153,19 @@ var Stop = NewHook("stop", ReverseOrder).OnError(Shutdown).ContinuePastError(tru
 // Start is a forward-order hook for starting services. If it encounters
 // an error, it will invoke Stop on whatever was started.
 var Start = NewHook("start", ForwardOrder).OnError(Stop)
+
+// Heartbeat is a forward-order hook meant for periodic work: lease and
+// distributed-lock refreshes, leader-election keepalives, and similar
+// callbacks that need to run on a schedule rather than once during
+// startup or shutdown. Register callbacks on it the same way as any
+// other hook, then call Hook.RunPeriodic to have nserve own the
+// ticking.
+var Heartbeat = NewHook("heartbeat", ForwardOrder)
+
+// Restart is a reverse-order hook meant to be used for graceful,
+// zero-downtime restarts. graceful.Restart (in the nserve/graceful
+// subpackage) duplicates listeners registered on Restart with
+// Hook.RegisterListener into a freshly exec'd copy of the running
+// binary and then invokes Stop on the parent; it does not invoke the
+// callbacks registered on Restart.
+var Restart = NewHook("restart", ReverseOrder)
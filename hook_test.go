@@ -0,0 +1,55 @@
+package nserve
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestWithTimeoutCancelsCallbackContext(t *testing.T) {
+	h := NewHook("timeout", ForwardOrder).WithTimeout(10 * time.Millisecond)
+
+	var sawDeadline bool
+	h.Register("slow", func(ctx context.Context) error {
+		select {
+		case <-ctx.Done():
+			sawDeadline = true
+			return ctx.Err()
+		case <-time.After(200 * time.Millisecond):
+			return nil
+		}
+	})
+
+	err := h.InvokeContext(context.Background())
+	if !sawDeadline {
+		t.Fatal("callback context was never canceled; WithTimeout had no effect")
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("InvokeContext() = %v, want an error wrapping context.DeadlineExceeded", err)
+	}
+}
+
+func TestWithoutTimeoutCallbackContextNotCanceled(t *testing.T) {
+	h := NewHook("notimeout", ForwardOrder)
+
+	h.Register("fast", func(ctx context.Context) error {
+		select {
+		case <-ctx.Done():
+			t.Error("callback context was canceled with no WithTimeout set")
+		default:
+		}
+		return nil
+	})
+
+	if err := h.InvokeContext(context.Background()); err != nil {
+		t.Fatalf("InvokeContext() = %v, want nil", err)
+	}
+}
+
+func TestWithTimeoutReturnsHookForChaining(t *testing.T) {
+	h := NewHook("chain", ForwardOrder)
+	if got := h.WithTimeout(5 * time.Millisecond); got != h {
+		t.Fatal("WithTimeout did not return the same *Hook for chaining")
+	}
+}
@@ -0,0 +1,219 @@
+package nserve
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Callback is the function signature for code registered with a Hook.
+// The context passed to a Callback is derived from the context given to
+// InvokeContext (or context.Background() for Invoke), and is canceled
+// once the callback returns or, if the Hook has a timeout set with
+// WithTimeout, once that timeout elapses.
+type Callback func(ctx context.Context) error
+
+// callbackEntry pairs a callback with the name it was registered under,
+// so errors and diagnostics can refer to where a failure came from.
+type callbackEntry struct {
+	name string
+	cb   Callback
+}
+
+// Register adds a callback to be invoked when the hook fires. name is
+// used only for error reporting. Callbacks run in the Hook's Order:
+// ForwardOrder runs them in registration order, ReverseOrder runs them
+// in the opposite order.
+// Register is thread-safe.
+func (h *Hook) Register(name string, cb Callback) *Hook {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.callbacks = append(h.callbacks, callbackEntry{name: name, cb: cb})
+	return h
+}
+
+// Invoke runs all callbacks registered on this hook with
+// context.Background(). It is equivalent to
+// InvokeContext(context.Background()).
+func (h *Hook) Invoke() error {
+	return h.InvokeContext(context.Background())
+}
+
+// InvokeContext runs all callbacks registered on this hook, deriving a
+// child context for each callback from ctx. If the Hook has a timeout
+// set with WithTimeout, that timeout bounds each callback's child
+// context individually. Invocation still waits for each callback to
+// return and records only the error it returns: callbacks that honor
+// cancellation will typically return ctx.Err()
+// (context.DeadlineExceeded) in that case, while a callback that
+// ignores ctx.Done() and returns nil after its budget is not reported
+// as failed. The child context is always canceled before invocation
+// moves on to the next callback.
+//
+// ErrorCombiner is optional. If it is set, errors are folded pairwise
+// as before. If it is unset, every callback error is accumulated into
+// an ErrorStack instead, and the ErrorStack is returned directly:
+// callers can get at the individual errors with
+// errors.As(err, &stack); for _, e := range stack.Errors() { ... }, and
+// errors.Is/errors.As against the returned error check every element,
+// not just the most recent one. If ContinuePast is false, invocation
+// stops at the first error either way. Once invocation is done, if it
+// produced an error, the InvokeOnError hooks are invoked via their own
+// InvokeContext (the error itself is not handed to them), so those
+// hooks also pick up anything ctx has registered on them.
+//
+// If ctx carries a HookRegistry (see WithHooks/RegisterOn), any
+// providers registered on it for h run too, in their registration
+// order, after h's own callbacks.
+//
+// Hooks created with ParallelOrder run their callbacks concurrently
+// instead, bounded by Hook.Concurrency; see invokeParallel.
+func (h *Hook) InvokeContext(ctx context.Context) error {
+	return h.invoke(ctx, nil)
+}
+
+// invoke is InvokeContext plus extra callbacks appended after h's own
+// (but before anything ctx's HookRegistry contributes). It exists so
+// that App.Invoke can fold in App.Providers without going through
+// Hook.Copy: callbacksForHook matches RegisterOn entries against h by
+// pointer identity, so invoking a copy of h would silently drop every
+// context-registered callback.
+func (h *Hook) invoke(ctx context.Context, extra []Callback) error {
+	h.lock.Lock()
+	callbacks := make([]callbackEntry, len(h.callbacks))
+	copy(callbacks, h.callbacks)
+	order := h.Order
+	timeout := h.timeout
+	combiner := h.ErrorCombiner
+	continuePast := h.ContinuePast
+	concurrency := h.concurrency
+	onError := make([]*Hook, len(h.InvokeOnError))
+	copy(onError, h.InvokeOnError)
+	h.lock.Unlock()
+
+	for _, cb := range extra {
+		callbacks = append(callbacks, callbackEntry{name: "provider", cb: cb})
+	}
+	for _, cb := range HooksFromContext(ctx).callbacksForHook(h) {
+		callbacks = append(callbacks, callbackEntry{name: "context", cb: cb})
+	}
+
+	var err error
+	switch order {
+	case ParallelOrder:
+		err = invokeParallel(ctx, callbacks, timeout, combiner, continuePast, concurrency)
+	default:
+		if order == ReverseOrder {
+			for i, j := 0, len(callbacks)-1; i < j; i, j = i+1, j-1 {
+				callbacks[i], callbacks[j] = callbacks[j], callbacks[i]
+			}
+		}
+		err = invokeSerial(ctx, callbacks, timeout, combiner, continuePast)
+	}
+
+	if err != nil {
+		for _, oe := range onError {
+			_ = oe.InvokeContext(ctx)
+		}
+	}
+	return err
+}
+
+// errorAccumulator folds callback errors together the same way
+// regardless of whether they arrive serially or concurrently: if
+// combiner is set, it folds pairwise; otherwise errors pile up on an
+// ErrorStack, which becomes the reported error.
+type errorAccumulator struct {
+	err   error
+	stack *ErrorStack
+}
+
+func (a *errorAccumulator) push(cerr error, combiner func(first, second error) error) {
+	if cerr == nil {
+		return
+	}
+	if combiner != nil {
+		if a.err != nil {
+			a.err = combiner(a.err, cerr)
+		} else {
+			a.err = cerr
+		}
+		return
+	}
+	if a.stack == nil {
+		a.stack = &ErrorStack{}
+	}
+	a.stack.Push(cerr)
+	a.err = a.stack
+}
+
+func invokeSerial(ctx context.Context, callbacks []callbackEntry, timeout time.Duration, combiner func(first, second error) error, continuePast bool) error {
+	var acc errorAccumulator
+	for _, entry := range callbacks {
+		if acc.err != nil && !continuePast {
+			break
+		}
+		var cctx context.Context
+		var cancel context.CancelFunc
+		if timeout > 0 {
+			cctx, cancel = context.WithTimeout(ctx, timeout)
+		} else {
+			cctx, cancel = context.WithCancel(ctx)
+		}
+		cerr := entry.cb(cctx)
+		cancel()
+		acc.push(cerr, combiner)
+	}
+	return acc.err
+}
+
+// invokeParallel runs every callback concurrently, bounded by
+// concurrency (unbounded if concurrency <= 0). If continuePast is
+// false, the first error cancels the shared context so that siblings
+// which honor cancellation can abort early instead of running to
+// completion after the hook has already decided to fail.
+func invokeParallel(ctx context.Context, callbacks []callbackEntry, timeout time.Duration, combiner func(first, second error) error, continuePast bool, concurrency int) error {
+	pctx, cancelAll := context.WithCancel(ctx)
+	defer cancelAll()
+
+	var sem chan struct{}
+	if concurrency > 0 {
+		sem = make(chan struct{}, concurrency)
+	}
+
+	var mu sync.Mutex
+	var acc errorAccumulator
+	var wg sync.WaitGroup
+
+	for _, entry := range callbacks {
+		entry := entry
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if sem != nil {
+				sem <- struct{}{}
+				defer func() { <-sem }()
+			}
+			var cctx context.Context
+			var cancel context.CancelFunc
+			if timeout > 0 {
+				cctx, cancel = context.WithTimeout(pctx, timeout)
+			} else {
+				cctx, cancel = context.WithCancel(pctx)
+			}
+			cerr := entry.cb(cctx)
+			cancel()
+			if cerr == nil {
+				return
+			}
+			mu.Lock()
+			acc.push(cerr, combiner)
+			if !continuePast {
+				cancelAll()
+			}
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	return acc.err
+}
@@ -0,0 +1,122 @@
+package nserve
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestParallelOrderRunsConcurrently proves that ParallelOrder callbacks
+// actually overlap rather than running one after another: each
+// callback blocks until every other callback has also started, which
+// can only complete if all of them are running at once.
+func TestParallelOrderRunsConcurrently(t *testing.T) {
+	const n = 5
+	h := NewHook("par", ParallelOrder)
+
+	var arrived sync.WaitGroup
+	arrived.Add(n)
+	started := make(chan struct{})
+
+	for i := 0; i < n; i++ {
+		h.Register("w", func(context.Context) error {
+			arrived.Done()
+			select {
+			case <-started:
+			case <-time.After(time.Second):
+				t.Error("timed out waiting for siblings to start; callbacks are not running concurrently")
+			}
+			return nil
+		})
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- h.Invoke() }()
+
+	go func() {
+		arrived.Wait()
+		close(started)
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Invoke() = %v, want nil", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Invoke() did not return; callbacks likely ran serially and deadlocked")
+	}
+}
+
+// TestParallelOrderConcurrencyLimit proves that Hook.Concurrency bounds
+// how many callbacks run at once.
+func TestParallelOrderConcurrencyLimit(t *testing.T) {
+	const n = 6
+	const limit = 2
+	h := NewHook("par", ParallelOrder).Concurrency(limit)
+
+	var running int32
+	var maxRunning int32
+	var mu sync.Mutex
+
+	for i := 0; i < n; i++ {
+		h.Register("w", func(context.Context) error {
+			cur := atomic.AddInt32(&running, 1)
+			mu.Lock()
+			if cur > maxRunning {
+				maxRunning = cur
+			}
+			mu.Unlock()
+			time.Sleep(20 * time.Millisecond)
+			atomic.AddInt32(&running, -1)
+			return nil
+		})
+	}
+
+	if err := h.Invoke(); err != nil {
+		t.Fatalf("Invoke() = %v, want nil", err)
+	}
+	if maxRunning > limit {
+		t.Fatalf("max concurrent callbacks = %d, want <= %d", maxRunning, limit)
+	}
+}
+
+// TestParallelOrderCancelsSiblingsOnError proves that when
+// ContinuePast is false, an error from one callback cancels the shared
+// context so that siblings which honor cancellation abort early
+// instead of running to completion.
+func TestParallelOrderCancelsSiblingsOnError(t *testing.T) {
+	h := NewHook("par", ParallelOrder)
+	boom := errors.New("boom")
+
+	h.Register("fails-fast", func(context.Context) error {
+		return boom
+	})
+
+	siblingErr := make(chan error, 1)
+	h.Register("honors-cancellation", func(ctx context.Context) error {
+		select {
+		case <-ctx.Done():
+			siblingErr <- ctx.Err()
+		case <-time.After(2 * time.Second):
+			siblingErr <- nil // never got canceled
+		}
+		return ctx.Err()
+	})
+
+	if err := h.Invoke(); !errors.Is(err, boom) {
+		t.Fatalf("Invoke() = %v, want %v", err, boom)
+	}
+
+	select {
+	case err := <-siblingErr:
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("sibling ctx err = %v, want context.Canceled", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("sibling never observed cancellation")
+	}
+}
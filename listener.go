@@ -0,0 +1,25 @@
+package nserve
+
+import "net"
+
+// RegisterListener records a listener so that a graceful restart (see
+// the nserve/graceful subpackage) can pass its underlying file
+// descriptor to a freshly started child process instead of the child
+// binding a fresh socket. Typically called from a Start callback.
+// RegisterListener is thread-safe.
+func (h *Hook) RegisterListener(l net.Listener) *Hook {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.listeners = append(h.listeners, l)
+	return h
+}
+
+// Listeners returns the listeners registered with RegisterListener, in
+// registration order. Listeners is thread-safe.
+func (h *Hook) Listeners() []net.Listener {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	out := make([]net.Listener, len(h.listeners))
+	copy(out, h.listeners)
+	return out
+}
@@ -0,0 +1,86 @@
+package nserve
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"sync/atomic"
+	"syscall"
+)
+
+// DefaultSignals is the set of signals HandleSignals listens for when
+// the caller doesn't supply its own list.
+var DefaultSignals = []os.Signal{os.Interrupt, syscall.SIGTERM}
+
+// stopRequested is the gate that StopRequested reports. It is
+// package-level rather than per-Hook because a single process-wide
+// stop request should be visible to every callback regardless of which
+// hook it was registered on.
+var stopRequested atomic.Bool
+
+// StopRequested reports whether a stop has been requested of the
+// process, via HandleSignals or SetStopRequested. Long-running Start
+// callbacks can poll this between steps of their own initialization to
+// abort early and cooperatively, the same way Terraform providers check
+// their stop hook between resource operations.
+func (h *Hook) StopRequested() bool {
+	return stopRequested.Load()
+}
+
+// SetStopRequested flips the stop-requested gate that StopRequested
+// reports. HandleSignals calls this on the first signal it receives;
+// callers wiring up their own signal handling, or tests, can call it
+// directly.
+func SetStopRequested(b bool) {
+	stopRequested.Store(b)
+}
+
+// HandleSignals binds app's Stop and Shutdown hooks to OS signals: the
+// first signal received invokes Stop (and flips the gate StopRequested
+// polls), and a second invokes Shutdown immediately rather than waiting
+// for Stop to finish or fail. This mirrors Terraform's stopHook, which
+// lets long-running operations poll for a requested stop rather than
+// being killed outright.
+//
+// If sigs is empty, DefaultSignals (SIGINT, SIGTERM) is used. The
+// returned cancel func stops listening for signals; call it once the
+// App has shut down through some other path so a later, unrelated
+// signal can't trigger Stop/Shutdown again.
+func HandleSignals(app *App, sigs ...os.Signal) (cancel func()) {
+	if len(sigs) == 0 {
+		sigs = DefaultSignals
+	}
+	ch := make(chan os.Signal, 2)
+	signal.Notify(ch, sigs...)
+	done := make(chan struct{})
+
+	go func() {
+		select {
+		case <-done:
+			signal.Stop(ch)
+			return
+		case <-ch:
+		}
+		stopRequested.Store(true)
+		stopDone := make(chan struct{})
+		go func() {
+			defer close(stopDone)
+			_ = app.Invoke(context.Background(), Stop)
+		}()
+		select {
+		case <-done:
+			signal.Stop(ch)
+			return
+		case <-stopDone:
+			return
+		case <-ch:
+			_ = app.Invoke(context.Background(), Shutdown)
+			return
+		}
+	}()
+
+	return func() {
+		signal.Stop(ch)
+		close(done)
+	}
+}
@@ -0,0 +1,132 @@
+package nserve
+
+import (
+	"context"
+	"os"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// raiseUSR1 sends SIGUSR1 to the current process, the same way an
+// external `kill` would signal it, without risking the test process
+// itself (unlike SIGINT/SIGTERM, SIGUSR1's default disposition is
+// terminate-without-core, but signal.Notify intercepts it before the
+// default disposition ever applies as long as cancel() hasn't run yet).
+func raiseUSR1(t *testing.T) {
+	t.Helper()
+	if err := syscall.Kill(os.Getpid(), syscall.SIGUSR1); err != nil {
+		t.Fatalf("raising SIGUSR1: %v", err)
+	}
+}
+
+func TestHandleSignalsInvokesStopOnFirstSignal(t *testing.T) {
+	defer SetStopRequested(false)
+
+	var stopped sync.WaitGroup
+	stopped.Add(1)
+	app := NewApp(Callback(func(context.Context) error {
+		stopped.Done()
+		return nil
+	}))
+
+	cancel := HandleSignals(app, syscall.SIGUSR1)
+	defer cancel()
+
+	raiseUSR1(t)
+
+	done := make(chan struct{})
+	go func() { stopped.Wait(); close(done) }()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Stop was not invoked after a single SIGUSR1")
+	}
+
+	if !Stop.StopRequested() {
+		t.Fatal("StopRequested() = false after HandleSignals received a signal, want true")
+	}
+}
+
+func TestHandleSignalsSecondSignalForcesShutdown(t *testing.T) {
+	defer SetStopRequested(false)
+
+	var stopStartedOnce sync.Once
+	stopStarted := make(chan struct{})
+	var shutdownOnce sync.Once
+	shutdownRan := make(chan struct{})
+
+	app := NewApp(Callback(func(context.Context) error {
+		stopStartedOnce.Do(func() { close(stopStarted) })
+		time.Sleep(500 * time.Millisecond)
+		return nil
+	}))
+
+	// Shutdown is a shared package-level hook; register a callback for
+	// the duration of this test only and don't rely on ordering against
+	// any other test that also touches Shutdown. It can fire twice here
+	// (once directly from the second signal, once via Stop's OnError
+	// cascade if Stop's own callback errors), so guard with sync.Once
+	// rather than double-closing shutdownRan.
+	Shutdown.Register("test-marker", func(context.Context) error {
+		shutdownOnce.Do(func() { close(shutdownRan) })
+		return nil
+	})
+
+	cancel := HandleSignals(app, syscall.SIGUSR1)
+	defer cancel()
+
+	raiseUSR1(t)
+	select {
+	case <-stopStarted:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Stop callback never started after first SIGUSR1")
+	}
+
+	raiseUSR1(t)
+	select {
+	case <-shutdownRan:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Shutdown was not invoked after a second SIGUSR1 arrived while Stop was still running")
+	}
+}
+
+func TestHandleSignalsCancelStopsListening(t *testing.T) {
+	defer SetStopRequested(false)
+
+	ran := make(chan struct{}, 1)
+	app := NewApp(Callback(func(context.Context) error {
+		select {
+		case ran <- struct{}{}:
+		default:
+		}
+		return nil
+	}))
+
+	cancel := HandleSignals(app, syscall.SIGUSR1)
+	cancel()
+
+	// Give the goroutine time to call signal.Stop before raising.
+	time.Sleep(50 * time.Millisecond)
+	raiseUSR1(t)
+
+	select {
+	case <-ran:
+		t.Fatal("Stop ran after cancel(), want signal handling to have stopped")
+	case <-time.After(200 * time.Millisecond):
+	}
+}
+
+func TestStopRequestedGate(t *testing.T) {
+	defer SetStopRequested(false)
+
+	SetStopRequested(false)
+	if Start.StopRequested() {
+		t.Fatal("StopRequested() = true after SetStopRequested(false)")
+	}
+	SetStopRequested(true)
+	if !Stop.StopRequested() {
+		t.Fatal("StopRequested() = false after SetStopRequested(true)")
+	}
+}